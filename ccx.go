@@ -60,27 +60,77 @@ type Ctx struct {
 	intent       Intent
 	cons         Constraints
 
-	mu       sync.RWMutex
-	state    string // "active"|"done"|"aborted"
-	doneCh   chan struct{}
-	err      error
-	children []string
+	mu        sync.RWMutex
+	state     string // "active"|"done"|"aborted"
+	doneCh    chan struct{}
+	err       error
+	cause     error
+	children  []string
+	clock     Clock
+	policyMgr *PolicyManager
+
+	mergeOnce sync.Once
+	mergedCh  chan struct{}
 }
 
 var _ context.Context = (*Ctx)(nil)
 
 // Background returns a root ccx context based on context.Background().
-func Background() *Ctx { return wrap(context.Background(), Intent{Name: ""}, Constraints{}) }
+func Background() *Ctx {
+	c := wrap(context.Background(), Intent{Name: ""}, Constraints{})
+	c.publishCreated()
+	return c
+}
 
 // TODO returns a root ccx context based on context.TODO().
-func TODO() *Ctx { return wrap(context.TODO(), Intent{Name: ""}, Constraints{}) }
+func TODO() *Ctx {
+	c := wrap(context.TODO(), Intent{Name: ""}, Constraints{})
+	c.publishCreated()
+	return c
+}
+
+func (c *Ctx) publishCreated() {
+	eventBus.publish(Event{NodeID: c.id, ParentID: c.parentID, Kind: Created, Intent: c.Intent(), Timestamp: c.Clock().Now(), MatchedPolicies: c.MatchedPolicies()})
+}
 
 func wrap(base context.Context, intent Intent, cons Constraints) *Ctx {
-	c := &Ctx{Context: base, id: newID(), intent: intent, cons: cons, state: "active", doneCh: make(chan struct{})}
+	c := &Ctx{Context: base, id: newID(), intent: intent, cons: cons, state: "active", doneCh: make(chan struct{}), clock: realClock{}, policyMgr: newPolicyManager()}
 	register(c)
 	return c
 }
 
+// watchDeadline keeps the ccx lifecycle state, DoneChan, ErrState, and any
+// Subscribers consistent with this node's deadline. It fires off c.Clock()
+// rather than the wall clock (see WithClock), so installing a *FakeClock
+// makes the abort deterministic under Advance instead of depending on a
+// real sleep; cancel releases the embedded context's resources once the
+// deadline fires. It also eagerly starts the same merge goroutine Done
+// uses, so an explicit cancel (or an ancestor's) that beats the deadline
+// still settles state via that goroutine's abort-then-close ordering. It
+// exits without leaking once the node finishes on its own via Fulfill/Abort.
+func (c *Ctx) watchDeadline(cancel context.CancelFunc) {
+	c.Done()
+
+	dur := c.cons.Deadline.Sub(c.clock.Now())
+	if dur <= 0 {
+		c.AbortCause(context.DeadlineExceeded)
+		cancel()
+		return
+	}
+	timer := c.clock.NewTimer(dur)
+	go func() {
+		select {
+		case <-timer.C():
+			if c.State() == "active" {
+				c.AbortCause(context.DeadlineExceeded)
+			}
+			cancel()
+		case <-c.doneCh:
+			timer.Stop()
+		}
+	}()
+}
+
 // Accessors
 
 // ID returns the unique identifier of this node.
@@ -104,6 +154,68 @@ func (c *Ctx) DoneChan() <-chan struct{} { return c.doneCh }
 // ErrState returns the abort error if the node aborted, or nil otherwise.
 func (c *Ctx) ErrState() error { c.mu.RLock(); defer c.mu.RUnlock(); return c.err }
 
+// Done overrides the embedded context.Context's Done so that *Ctx satisfies
+// the usual `select { case <-ctx.Done(): ... }` idiom: it closes when either
+// this node finishes (Fulfill/Abort) or the embedded parent context is
+// canceled, whichever happens first. When the embedded context wins the
+// race, the same goroutine first settles State/ErrState via AbortCause and
+// only then closes the merged channel, so a waiter that wakes up on
+// <-Done() never observes a still-"active" node.
+//
+// If the embedded context can never finish on its own (e.g. a root built on
+// context.Background()/context.TODO(), whose Done is nil), there is nothing
+// to merge: Done reuses doneCh directly instead of leaking a goroutine that
+// would otherwise block for the life of the process.
+func (c *Ctx) Done() <-chan struct{} {
+	c.mergeOnce.Do(func() {
+		if c.Context.Done() == nil {
+			c.mergedCh = c.doneCh
+			return
+		}
+		c.mergedCh = make(chan struct{})
+		go func() {
+			select {
+			case <-c.doneCh:
+			case <-c.Context.Done():
+				if c.State() == "active" {
+					c.AbortCause(c.Context.Err())
+				}
+			}
+			close(c.mergedCh)
+		}()
+	})
+	return c.mergedCh
+}
+
+// Err overrides the embedded context.Context's Err so it reflects this
+// node's own Abort reason once set; it falls back to the embedded parent
+// context's Err otherwise. Whichever side finishes first determines the
+// return value, so the other side's error is preserved only as a fallback
+// (see Cause for retrieving the original reason regardless of which side
+// finished first).
+func (c *Ctx) Err() error {
+	c.mu.RLock()
+	err := c.err
+	c.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	return c.Context.Err()
+}
+
+// Deadline overrides the embedded context.Context's Deadline so it reflects
+// this node's own Constraints.Deadline, clamped by clampChild at WithIntent
+// time. WithIntent builds every child on context.WithCancel rather than
+// context.WithDeadline (see watchDeadline), so the embedded context never
+// carries a deadline of its own; without this override Deadline would
+// report the nearest ancestor's deadline, or none, instead of this node's.
+func (c *Ctx) Deadline() (time.Time, bool) {
+	if !c.cons.Deadline.IsZero() {
+		return c.cons.Deadline, true
+	}
+	return c.Context.Deadline()
+}
+
 // Lifecycle
 
 // Fulfill marks the node as successfully completed and closes DoneChan.
@@ -114,28 +226,111 @@ func (c *Ctx) Fulfill() {
 		close(c.doneCh)
 	}
 	c.mu.Unlock()
+	registry.Put(snapshotOf(c))
+	eventBus.publish(Event{NodeID: c.id, ParentID: c.parentID, Kind: Fulfilled, Intent: c.Intent(), Timestamp: c.Clock().Now(), MatchedPolicies: c.MatchedPolicies()})
 }
 
-// Abort marks the node as aborted with an error and closes DoneChan.
-func (c *Ctx) Abort(err error) {
+// Abort marks the node as aborted with an error and closes DoneChan. It is
+// equivalent to AbortCause(err); the err also becomes the node's Cause.
+func (c *Ctx) Abort(err error) { c.AbortCause(err) }
+
+// AbortCause marks the node as aborted, recording err as both the node's
+// ErrState/Err and its Cause. Use Cause to retrieve the original reason even
+// in places where Err has collapsed to a stdlib sentinel such as
+// context.Canceled or context.DeadlineExceeded.
+func (c *Ctx) AbortCause(err error) {
 	c.mu.Lock()
 	if c.state == "active" {
 		c.state = "aborted"
 		c.err = err
+		c.cause = err
 		close(c.doneCh)
 	}
 	c.mu.Unlock()
+	registry.Put(snapshotOf(c))
+	eventBus.publish(Event{NodeID: c.id, ParentID: c.parentID, Kind: Aborted, Intent: c.Intent(), Cause: err, Timestamp: c.Clock().Now(), MatchedPolicies: c.MatchedPolicies()})
+}
+
+// Cause returns the original reason this node (or, failing that, its
+// embedded parent context) was canceled/aborted for. Unlike Err, Cause is
+// not limited to stdlib sentinel values: it returns whatever error was
+// passed to Abort/AbortCause, falling back to context.Cause of the embedded
+// context, and finally to its plain Err.
+func Cause(c *Ctx) error {
+	c.mu.RLock()
+	cause := c.cause
+	c.mu.RUnlock()
+	if cause != nil {
+		return cause
+	}
+	if cc := context.Cause(c.Context); cc != nil {
+		return cc
+	}
+	return c.Context.Err()
 }
 
-// Lineage registry (in-process)
+// Lineage registry
 
+// reg is a local, in-process cache of live *Ctx pointers for nodes created
+// in this process. It exists alongside the pluggable Registry (see
+// registry.go) so that in-process lineage keeps pointer identity and live
+// channels even when a remote Registry is installed; the Registry is the
+// source of truth for nodes that live elsewhere.
 var reg sync.Map // id -> *Ctx
 
-func register(c *Ctx) { reg.Store(c.id, c) }
+func register(c *Ctx) {
+	reg.Store(c.id, c)
+	registry.Put(snapshotOf(c))
+	if registry != defaultRegistry {
+		c.watchRegistry()
+	}
+}
+
+// watchRegistry applies RegistryEvents published for this node by another
+// process sharing a remote Registry (see SetRegistry) back onto this node,
+// translating a remote SendCancel/SendAdjust into the same local effect
+// SendCancel/SendAdjust produced in the process that called it, scoped the
+// same way. It runs until the Registry closes the Watch channel; the
+// in-memory default Registry never reaches here, since a single process has
+// no remote side to watch for.
+func (c *Ctx) watchRegistry() {
+	ch := registry.Watch(c.id)
+	go func() {
+		for ev := range ch {
+			switch ev.Kind {
+			case RegistryCancel:
+				switch ev.Scope {
+				case ScopeNode:
+					c.AbortCause(ev.Reason)
+				case ScopeSubtree:
+					c.abortRecursive(ev.Reason)
+				case ScopeRoot:
+					c.Root().abortRecursive(ev.Reason)
+				}
+			case RegistryAdjust:
+				switch ev.Scope {
+				case ScopeNode:
+					c.applyAdjust(ev.Adjust)
+				case ScopeSubtree:
+					c.adjustRecursive(ev.Adjust)
+				case ScopeRoot:
+					c.Root().adjustRecursive(ev.Adjust)
+				}
+			case RegistryPut:
+				// Snapshot-only change; nothing to replicate beyond what
+				// Put already recorded in the Registry.
+			}
+		}
+	}()
+}
+
 func get(id string) (*Ctx, bool) {
 	if v, ok := reg.Load(id); ok {
 		return v.(*Ctx), true
 	}
+	if snap, ok := registry.Get(id); ok {
+		return ctxFromSnapshot(snap), true
+	}
 	return nil, false
 }
 
@@ -151,11 +346,26 @@ func (c *Ctx) Root() *Ctx {
 	return cur
 }
 
-// Children returns the direct children of this node.
+// Children returns the direct children of this node, merging the local,
+// pointer-identity children created in this process with any additional
+// children the Registry knows about (e.g. created by another process
+// sharing this lineage).
 func (c *Ctx) Children() []*Ctx {
 	c.mu.RLock()
 	ids := append([]string(nil), c.children...)
 	c.mu.RUnlock()
+
+	seen := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		seen[id] = struct{}{}
+	}
+	for _, id := range registry.Children(c.id) {
+		if _, ok := seen[id]; !ok {
+			ids = append(ids, id)
+			seen[id] = struct{}{}
+		}
+	}
+
 	out := make([]*Ctx, 0, len(ids))
 	for _, id := range ids {
 		if ch, ok := get(id); ok {
@@ -170,18 +380,19 @@ func (c *Ctx) Children() []*Ctx {
 // deadline than the parent, the parent's deadline is used.
 func WithIntent(parent *Ctx, intent Intent, cons Constraints) (*Ctx, context.CancelFunc) {
 	cons = clampChild(parent.cons, cons)
-	var base context.Context
-	var cancel context.CancelFunc
-	if cons.Deadline.IsZero() {
-		base, cancel = context.WithCancel(parent.Context)
-	} else {
-		base, cancel = context.WithDeadline(parent.Context, cons.Deadline)
-	}
+	base, cancel := context.WithCancel(parent.Context)
 	child := wrap(base, intent, cons)
 	child.parentID = parent.id
+	child.clock = parent.Clock()
+	child.policyMgr = parent.PolicyManager()
 	parent.mu.Lock()
 	parent.children = append(parent.children, child.id)
 	parent.mu.Unlock()
+	if !cons.Deadline.IsZero() {
+		child.watchDeadline(cancel)
+	}
+	registry.Put(snapshotOf(child))
+	child.publishCreated()
 	return child, cancel
 }
 
@@ -193,33 +404,57 @@ func clampChild(p Constraints, c Constraints) Constraints {
 	return out
 }
 
+// WithTimeout derives a child context from parent with a deadline d after
+// parent's current time, as seen by parent.Clock(). It is sugar for
+// WithIntent with an explicit Constraints.Deadline; pass a *FakeClock to
+// parent (see WithClock) to make the timeout fire deterministically under
+// Advance instead of a real sleep.
+func WithTimeout(parent *Ctx, d time.Duration, intent Intent) (*Ctx, context.CancelFunc) {
+	return WithIntent(parent, intent, Constraints{Deadline: parent.Clock().Now().Add(d)})
+}
+
+// WithDeadline derives a child context from parent with an absolute
+// deadline. It is sugar for WithIntent with an explicit
+// Constraints.Deadline; clampChild still applies, so a deadline later than
+// parent's own is clamped down to it.
+func WithDeadline(parent *Ctx, deadline time.Time, intent Intent) (*Ctx, context.CancelFunc) {
+	return WithIntent(parent, intent, Constraints{Deadline: deadline})
+}
+
 // Scoped controls
 
 // SendCancel applies a cancellation to a node at the specified scope (Node,
-// Subtree, or Root).
+// Subtree, or Root). reason is propagated unchanged to every aborted node as
+// its Cause, so Cause(node) returns the original reason regardless of how
+// deep in the subtree the node sits. The cancel is also published to the
+// Registry so that other processes watching this lineage (via a remote
+// Registry such as ccxetcd) can translate it into a local Abort.
 func (c *Ctx) SendCancel(scope Scope, reason error) {
 	switch scope {
 	case ScopeNode:
-		c.Abort(reason)
+		c.AbortCause(reason)
 	case ScopeSubtree:
 		c.abortRecursive(reason)
 	case ScopeRoot:
 		c.Root().abortRecursive(reason)
 	}
+	registry.Publish(RegistryEvent{Kind: RegistryCancel, NodeID: c.id, Scope: scope, Reason: reason})
 }
 
 func (c *Ctx) abortRecursive(reason error) {
 	if c.State() == "active" {
-		c.Abort(reason)
+		c.AbortCause(reason)
 	}
 	for _, ch := range c.Children() {
 		ch.abortRecursive(reason)
 	}
 }
 
-// SendAdjust applies an AdjustFn to Intent.Params at the specified scope. In
-// this minimal, in-process implementation, adjustments are last-writer-wins;
-// prefer idempotent updates and namespaced keys.
+// SendAdjust applies an AdjustFn to Intent.Params at the specified scope.
+// Adjustments are last-writer-wins; prefer idempotent updates and
+// namespaced keys, since a remote Registry merges adjustments from
+// multiple processes on that basis. The adjustment is also published to
+// the Registry for any process watching this lineage.
 func (c *Ctx) SendAdjust(scope Scope, fn AdjustFn) {
 	switch scope {
 	case ScopeNode:
@@ -229,6 +464,7 @@ func (c *Ctx) SendAdjust(scope Scope, fn AdjustFn) {
 	case ScopeRoot:
 		c.Root().adjustRecursive(fn)
 	}
+	registry.Publish(RegistryEvent{Kind: RegistryAdjust, NodeID: c.id, Scope: scope, Adjust: fn})
 }
 
 func (c *Ctx) applyAdjust(fn AdjustFn) {
@@ -238,6 +474,8 @@ func (c *Ctx) applyAdjust(fn AdjustFn) {
 	}
 	fn(c.intent.Params)
 	c.mu.Unlock()
+	registry.Put(snapshotOf(c))
+	eventBus.publish(Event{NodeID: c.id, ParentID: c.parentID, Kind: Adjusted, Intent: c.Intent(), Timestamp: c.Clock().Now(), MatchedPolicies: c.MatchedPolicies()})
 }
 
 func (c *Ctx) adjustRecursive(fn AdjustFn) {
@@ -326,8 +564,26 @@ func fromPolScope(s policy.Scope) Scope {
 	}
 }
 
-// EvaluatePolicies delegates to the external policy module using the adapter.
-func EvaluatePolicies(n *Ctx) []policy.Decision { return policy.Evaluate(policyNode{n}) }
+// EvaluatePolicies delegates to the external policy module using the
+// adapter, then uses n's PolicyManager to record which policies matched
+// and to drop any ScopeSubtree/ScopeRoot decision an ancestor already
+// satisfied, so the same subtree-wide decision isn't re-applied at every
+// node down the tree.
+func EvaluatePolicies(n *Ctx) []policy.Decision {
+	all := policy.Evaluate(policyNode{n})
+	mgr := n.PolicyManager()
+	name := n.Intent().Name
+
+	out := make([]policy.Decision, 0, len(all))
+	for _, d := range all {
+		mgr.mark(n.id, name, d.PolicyID)
+		if (d.Scope == policy.ScopeSubtree || d.Scope == policy.ScopeRoot) && mgr.satisfiedByAncestor(n, d.PolicyID) {
+			continue
+		}
+		out = append(out, d)
+	}
+	return out
+}
 
 // EnforcePolicies applies decisions onto this node using the external module.
 func EnforcePolicies(n *Ctx, ds []policy.Decision) { policy.Enforce(policyEnforcer{n}, ds) }
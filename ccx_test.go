@@ -74,6 +74,48 @@ func TestSendAdjustScopes(t *testing.T) {
 	}
 }
 
+func TestCtxErrAndDoneReflectAbort(t *testing.T) {
+	root := ccx.Background()
+	boom := errors.New("boom")
+
+	select {
+	case <-root.Done():
+		t.Fatal("did not expect Done to be closed before Abort")
+	default:
+	}
+	if root.Err() != nil {
+		t.Fatalf("expected nil Err before Abort, got %v", root.Err())
+	}
+
+	root.Abort(boom)
+
+	select {
+	case <-root.Done():
+		// ok
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("expected Done to close after Abort")
+	}
+	if root.Err() != boom {
+		t.Fatalf("expected Err to reflect Abort reason, got %v", root.Err())
+	}
+	if ccx.Cause(root) != boom {
+		t.Fatalf("expected Cause to return original reason, got %v", ccx.Cause(root))
+	}
+}
+
+func TestSendCancelPreservesCause(t *testing.T) {
+	root := ccx.Background()
+	a, _ := ccx.WithIntent(root, ccx.Intent{Name: "A"}, ccx.Constraints{})
+	b, _ := ccx.WithIntent(a, ccx.Intent{Name: "B"}, ccx.Constraints{})
+
+	boom := errors.New("boom")
+	a.SendCancel(ccx.ScopeSubtree, boom)
+
+	if ccx.Cause(a) != boom || ccx.Cause(b) != boom {
+		t.Fatalf("expected cause preserved across subtree, got a=%v b=%v", ccx.Cause(a), ccx.Cause(b))
+	}
+}
+
 func TestWaitAllAndWhenAny(t *testing.T) {
 	root := ccx.Background()
 	n1, _ := ccx.WithIntent(root, ccx.Intent{Name: "N1"}, ccx.Constraints{})
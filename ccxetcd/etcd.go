@@ -0,0 +1,261 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ccxetcd is an etcd v3-backed ccx.Registry, letting a cascading
+// context's lineage span processes: nodes are stored under a namespaced
+// key prefix with a lease for liveness, and SendCancel/SendAdjust are
+// translated into watch events that every process sharing the lineage
+// observes.
+package ccxetcd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	ccx "github.com/ArieDeha/ccx"
+)
+
+// Registry is a ccx.Registry backed by etcd v3. Zero value is not usable;
+// construct with New.
+type Registry struct {
+	cli      *clientv3.Client
+	prefix   string
+	leaseTTL int64
+
+	leaseID clientv3.LeaseID
+}
+
+// Option configures a Registry.
+type Option func(*Registry)
+
+// WithLeaseTTL sets the etcd lease TTL (seconds) used to mark this
+// process's nodes as live; it defaults to 30s. New keeps the lease alive
+// for the lifetime of the ctx passed to it.
+func WithLeaseTTL(seconds int64) Option {
+	return func(r *Registry) { r.leaseTTL = seconds }
+}
+
+// New returns a Registry that stores lineage under prefix (e.g.
+// "/ccx/lineage/") in cli. It grants and keeps alive an etcd lease for the
+// lifetime of ctx so nodes registered by this process are automatically
+// pruned if the process disappears.
+func New(ctx context.Context, cli *clientv3.Client, prefix string, opts ...Option) (*Registry, error) {
+	r := &Registry{cli: cli, prefix: strings.TrimSuffix(prefix, "/"), leaseTTL: 30}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	lease, err := cli.Grant(ctx, r.leaseTTL)
+	if err != nil {
+		return nil, fmt.Errorf("ccxetcd: grant lease: %w", err)
+	}
+	r.leaseID = lease.ID
+
+	keepAlive, err := cli.KeepAlive(ctx, r.leaseID)
+	if err != nil {
+		return nil, fmt.Errorf("ccxetcd: keepalive lease: %w", err)
+	}
+	go func() {
+		for range keepAlive {
+			// Drain responses; etcd requires the channel to be consumed.
+		}
+	}()
+
+	return r, nil
+}
+
+func (r *Registry) nodeKey(id string) string { return fmt.Sprintf("%s/nodes/%s", r.prefix, id) }
+func (r *Registry) childKey(parent, id string) string {
+	return fmt.Sprintf("%s/children/%s/%s", r.prefix, parent, id)
+}
+func (r *Registry) eventKey(nodeID string) string {
+	return fmt.Sprintf("%s/events/%s", r.prefix, nodeID)
+}
+
+// wireSnapshot is the JSON-serializable form of ccx.Snapshot stored in etcd.
+// ccx.Snapshot carries an error value, which does not round-trip through
+// JSON, so it is reduced to its message here; Get reconstructs a plain
+// error from it.
+type wireSnapshot struct {
+	ID, ParentID string
+	IntentName   string
+	Params       map[string]any
+	Deadline     int64 // UnixNano, 0 if zero
+	State        string
+	ErrMsg       string
+}
+
+// Put stores snap under its node key with this Registry's lease attached,
+// and indexes it under its parent's children key so Children can answer
+// without a prefix scan per call.
+func (r *Registry) Put(snap ccx.Snapshot) {
+	ws := toWire(snap)
+	data, err := json.Marshal(ws)
+	if err != nil {
+		return
+	}
+	ctx := context.Background()
+	_, _ = r.cli.Put(ctx, r.nodeKey(snap.ID), string(data), clientv3.WithLease(r.leaseID))
+	if snap.ParentID != "" {
+		_, _ = r.cli.Put(ctx, r.childKey(snap.ParentID, snap.ID), "", clientv3.WithLease(r.leaseID))
+	}
+}
+
+// Get fetches the last known snapshot for id.
+func (r *Registry) Get(id string) (ccx.Snapshot, bool) {
+	resp, err := r.cli.Get(context.Background(), r.nodeKey(id))
+	if err != nil || len(resp.Kvs) == 0 {
+		return ccx.Snapshot{}, false
+	}
+	var ws wireSnapshot
+	if err := json.Unmarshal(resp.Kvs[0].Value, &ws); err != nil {
+		return ccx.Snapshot{}, false
+	}
+	return fromWire(ws), true
+}
+
+// Children lists the IDs indexed under id's children key prefix.
+func (r *Registry) Children(id string) []string {
+	prefix := fmt.Sprintf("%s/children/%s/", r.prefix, id)
+	resp, err := r.cli.Get(context.Background(), prefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil
+	}
+	out := make([]string, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		out = append(out, strings.TrimPrefix(string(kv.Key), prefix))
+	}
+	return out
+}
+
+// Watch subscribes to cancel/adjust events published for id via etcd's
+// watch API. The watch runs on context.Background(), not on the ctx passed
+// to New, so the returned channel is closed only when etcd ends the watch
+// stream (e.g. cli is closed); callers that need to stop earlier should not
+// rely on this channel closing and should instead stop consuming it.
+func (r *Registry) Watch(id string) <-chan ccx.RegistryEvent {
+	out := make(chan ccx.RegistryEvent, 16)
+	wch := r.cli.Watch(context.Background(), r.eventKey(id))
+	go func() {
+		defer close(out)
+		for resp := range wch {
+			for _, wev := range resp.Events {
+				if wev.Type != clientv3.EventTypePut {
+					continue
+				}
+				var ev wireEvent
+				if err := json.Unmarshal(wev.Kv.Value, &ev); err != nil {
+					continue
+				}
+				out <- ev.toRegistryEvent()
+			}
+		}
+	}()
+	return out
+}
+
+// wireEvent is the JSON-serializable form of ccx.RegistryEvent. AdjustFn is
+// not serializable, so remote adjust events instead carry the params to
+// merge; toRegistryEvent builds an AdjustFn on the receiving side that
+// applies them with last-writer-wins semantics, matching ccx's SendAdjust
+// guidance.
+type wireEvent struct {
+	Kind      ccx.RegistryEventKind
+	NodeID    string
+	Scope     ccx.Scope
+	ReasonMsg string
+	Params    map[string]any
+}
+
+func (e wireEvent) toRegistryEvent() ccx.RegistryEvent {
+	ev := ccx.RegistryEvent{Kind: e.Kind, NodeID: e.NodeID, Scope: e.Scope}
+	if e.ReasonMsg != "" {
+		ev.Reason = fmt.Errorf("%s", e.ReasonMsg)
+	}
+	if e.Kind == ccx.RegistryAdjust {
+		params := e.Params
+		ev.Adjust = func(m map[string]any) {
+			for k, v := range params {
+				m[k] = v
+			}
+		}
+	}
+	return ev
+}
+
+// Publish writes ev to its node's event key so every process Watching that
+// key observes it. ev.Adjust is an arbitrary closure and cannot be
+// serialized directly, so for RegistryAdjust events Publish resolves it
+// against an empty map first and ships the resulting params in
+// wireEvent.Params; Watch rebuilds an equivalent merge closure from those
+// params on the receiving side.
+//
+// A single etcd key's Watch stream is delivered in strictly increasing
+// ModRevision order, so concurrent Publish calls for the same NodeID are
+// already observed by every watcher in one global, last-writer-wins order
+// without wireEvent needing its own revision field.
+func (r *Registry) Publish(ev ccx.RegistryEvent) {
+	we := wireEvent{Kind: ev.Kind, NodeID: ev.NodeID, Scope: ev.Scope}
+	if ev.Reason != nil {
+		we.ReasonMsg = ev.Reason.Error()
+	}
+	if ev.Kind == ccx.RegistryAdjust && ev.Adjust != nil {
+		params := map[string]any{}
+		ev.Adjust(params)
+		we.Params = params
+	}
+	data, err := json.Marshal(we)
+	if err != nil {
+		return
+	}
+	_, _ = r.cli.Put(context.Background(), r.eventKey(ev.NodeID), string(data))
+}
+
+func toWire(snap ccx.Snapshot) wireSnapshot {
+	ws := wireSnapshot{
+		ID:         snap.ID,
+		ParentID:   snap.ParentID,
+		IntentName: string(snap.Intent.Name),
+		Params:     snap.Intent.Params,
+		State:      snap.State,
+	}
+	if !snap.Cons.Deadline.IsZero() {
+		ws.Deadline = snap.Cons.Deadline.UnixNano()
+	}
+	if snap.Err != nil {
+		ws.ErrMsg = snap.Err.Error()
+	}
+	return ws
+}
+
+func fromWire(ws wireSnapshot) ccx.Snapshot {
+	snap := ccx.Snapshot{
+		ID:       ws.ID,
+		ParentID: ws.ParentID,
+		Intent:   ccx.Intent{Name: ccx.IntentName(ws.IntentName), Params: ws.Params},
+		State:    ws.State,
+	}
+	if ws.Deadline != 0 {
+		snap.Cons.Deadline = time.Unix(0, ws.Deadline)
+	}
+	if ws.ErrMsg != "" {
+		snap.Err = fmt.Errorf("%s", ws.ErrMsg)
+	}
+	return snap
+}
@@ -0,0 +1,159 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccx
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Timer is the subset of time.Timer that Clock implementations must provide.
+type Timer interface {
+	// C returns the channel on which the time is delivered when the timer
+	// fires.
+	C() <-chan time.Time
+	// Stop prevents the Timer from firing, as time.Timer.Stop does.
+	Stop() bool
+}
+
+// Clock abstracts time so deadline clamping, timeout wiring, and
+// elapsed-time policy decisions can be driven deterministically in tests
+// instead of depending on the wall clock and real sleeps.
+type Clock interface {
+	// Now returns the current time as seen by this clock.
+	Now() time.Time
+	// After returns a channel that receives the current time after d has
+	// elapsed, as time.After does.
+	After(d time.Duration) <-chan time.Time
+	// NewTimer returns a Timer that fires after d, as time.NewTimer does.
+	NewTimer(d time.Duration) Timer
+}
+
+// realClock is the default Clock backed by the time package.
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (realClock) NewTimer(d time.Duration) Timer         { return realTimer{time.NewTimer(d)} }
+
+type realTimer struct{ t *time.Timer }
+
+func (r realTimer) C() <-chan time.Time { return r.t.C }
+func (r realTimer) Stop() bool          { return r.t.Stop() }
+
+// WithClock sets the Clock used by c and every node derived from it via
+// WithIntent going forward. Pass a *FakeClock in tests to make deadline
+// clamping, timeout firing, and any elapsed-time policy decisions
+// deterministic. WithClock returns c for convenient chaining, e.g.
+//
+//	root := ccx.WithClock(ccx.Background(), ccx.NewFakeClock(time.Unix(0, 0)))
+func WithClock(c *Ctx, clock Clock) *Ctx {
+	c.mu.Lock()
+	c.clock = clock
+	c.mu.Unlock()
+	return c
+}
+
+// Clock returns the Clock in effect for this node: the one set via
+// WithClock on it or an ancestor, or the real wall clock by default.
+func (c *Ctx) Clock() Clock {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.clock
+}
+
+// fakeTimer is the Timer implementation returned by FakeClock.
+type fakeTimer struct {
+	fc     *FakeClock
+	ch     chan time.Time
+	fireAt time.Time
+}
+
+func (t *fakeTimer) C() <-chan time.Time { return t.ch }
+func (t *fakeTimer) Stop() bool          { return t.fc.stopTimer(t) }
+
+// FakeClock is a Clock that only advances when Advance is called, making
+// deadline-based aborts, timeouts, and subtree timeout propagation fire
+// deterministically in tests instead of relying on sleeps.
+type FakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+// NewFakeClock returns a FakeClock initialized to now.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now returns the clock's current virtual time.
+func (f *FakeClock) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// After returns a channel that fires once the clock has been Advanced by at
+// least d from now.
+func (f *FakeClock) After(d time.Duration) <-chan time.Time {
+	return f.NewTimer(d).C()
+}
+
+// NewTimer returns a fake Timer that fires once the clock has been Advanced
+// by at least d from now.
+func (f *FakeClock) NewTimer(d time.Duration) Timer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	t := &fakeTimer{fc: f, ch: make(chan time.Time, 1), fireAt: f.now.Add(d)}
+	f.timers = append(f.timers, t)
+	return t
+}
+
+// Advance moves the clock forward by d, synchronously firing (in fire-time
+// order) every pending timer whose fire time is at or before the new time.
+func (f *FakeClock) Advance(d time.Duration) {
+	f.mu.Lock()
+	f.now = f.now.Add(d)
+	now := f.now
+	var due []*fakeTimer
+	remaining := f.timers[:0]
+	for _, t := range f.timers {
+		if !t.fireAt.After(now) {
+			due = append(due, t)
+		} else {
+			remaining = append(remaining, t)
+		}
+	}
+	f.timers = remaining
+	f.mu.Unlock()
+
+	sort.Slice(due, func(i, j int) bool { return due[i].fireAt.Before(due[j].fireAt) })
+	for _, t := range due {
+		t.ch <- now
+	}
+}
+
+func (f *FakeClock) stopTimer(t *fakeTimer) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, cur := range f.timers {
+		if cur == t {
+			f.timers = append(f.timers[:i], f.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package ccx_test
+
+import (
+	"testing"
+	"time"
+
+	ccx "github.com/ArieDeha/ccx"
+)
+
+func TestFakeClockAdvanceFiresTimers(t *testing.T) {
+	fc := ccx.NewFakeClock(time.Unix(0, 0))
+	timer := fc.NewTimer(10 * time.Second)
+
+	select {
+	case <-timer.C():
+		t.Fatal("timer fired before Advance")
+	default:
+	}
+
+	fc.Advance(5 * time.Second)
+	select {
+	case <-timer.C():
+		t.Fatal("timer fired before its deadline")
+	default:
+	}
+
+	fc.Advance(5 * time.Second)
+	select {
+	case <-timer.C():
+		// ok
+	default:
+		t.Fatal("expected timer to fire once the clock reached its deadline")
+	}
+}
+
+func TestWithClockInheritedByChildren(t *testing.T) {
+	fc := ccx.NewFakeClock(time.Unix(0, 0))
+	root := ccx.WithClock(ccx.Background(), fc)
+	child, cancel := ccx.WithIntent(root, ccx.Intent{Name: "Child"}, ccx.Constraints{})
+	defer cancel()
+
+	if root.Clock() != fc || child.Clock() != fc {
+		t.Fatal("expected child to inherit the root's fake clock")
+	}
+}
@@ -0,0 +1,126 @@
+package ccx_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ccx "github.com/ArieDeha/ccx"
+)
+
+func TestWithTimeoutAbortsOnDeadline(t *testing.T) {
+	root := ccx.Background()
+	child, cancel := ccx.WithTimeout(root, 20*time.Millisecond, ccx.Intent{Name: "Timeout"})
+	defer cancel()
+
+	select {
+	case <-child.Done():
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for WithTimeout to abort the child")
+	}
+	if child.State() != "aborted" {
+		t.Fatalf("expected aborted state, got %s", child.State())
+	}
+	if child.Err() != context.DeadlineExceeded {
+		t.Fatalf("expected DeadlineExceeded, got %v", child.Err())
+	}
+}
+
+func TestWithDeadlineCancelBeforeDeadlineReportsCanceled(t *testing.T) {
+	root := ccx.Background()
+	child, cancel := ccx.WithDeadline(root, time.Now().Add(time.Hour), ccx.Intent{Name: "Deadline"})
+
+	cancel()
+
+	select {
+	case <-child.Done():
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for explicit cancel to abort the child")
+	}
+	if child.State() != "aborted" {
+		t.Fatalf("expected aborted state, got %s", child.State())
+	}
+	if child.Err() != context.Canceled {
+		t.Fatalf("expected Canceled, got %v", child.Err())
+	}
+}
+
+func TestWithTimeoutFulfillStopsWatcherWithoutAborting(t *testing.T) {
+	root := ccx.Background()
+	child, cancel := ccx.WithTimeout(root, time.Hour, ccx.Intent{Name: "Timeout"})
+	defer cancel()
+
+	child.Fulfill()
+
+	select {
+	case <-child.Done():
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for Fulfill to close Done")
+	}
+	if child.State() != "done" {
+		t.Fatalf("expected Fulfill to win the race, got state %s", child.State())
+	}
+}
+
+func TestWithTimeoutFiresOnFakeClockAdvanceNotWallClock(t *testing.T) {
+	fc := ccx.NewFakeClock(time.Unix(0, 0))
+	root := ccx.WithClock(ccx.Background(), fc)
+	child, cancel := ccx.WithTimeout(root, 10*time.Second, ccx.Intent{Name: "Timeout"})
+	defer cancel()
+
+	select {
+	case <-child.Done():
+		t.Fatal("expected the timeout not to fire before Advance")
+	default:
+	}
+
+	fc.Advance(5 * time.Second)
+	select {
+	case <-child.Done():
+		t.Fatal("expected the timeout not to fire before its full duration has elapsed on the fake clock")
+	default:
+	}
+
+	fc.Advance(5 * time.Second)
+	select {
+	case <-child.Done():
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for Advance to fire the timeout")
+	}
+	if child.State() != "aborted" {
+		t.Fatalf("expected aborted state, got %s", child.State())
+	}
+	if child.Err() != context.DeadlineExceeded {
+		t.Fatalf("expected DeadlineExceeded, got %v", child.Err())
+	}
+}
+
+// BenchmarkCommonParentCancel is modeled on the stdlib context package's
+// benchmark of the same name: n children share one parent with a deadline,
+// and we measure how long canceling the parent takes to unwind the whole
+// subtree. Every child here also runs its own deadline watcher goroutine
+// (added to wire WithTimeout/WithDeadline into Abort), so this is the
+// regression check for that goroutine's cost at depth.
+func BenchmarkCommonParentCancel(b *testing.B) {
+	const n = 1000
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		root := ccx.Background()
+		parent, parentCancel := ccx.WithDeadline(root, time.Now().Add(time.Hour), ccx.Intent{Name: "Parent"})
+		children := make([]*ccx.Ctx, n)
+		cancels := make([]context.CancelFunc, n)
+		for j := range children {
+			children[j], cancels[j] = ccx.WithDeadline(parent, time.Now().Add(time.Hour), ccx.Intent{Name: "Child"})
+		}
+		b.StartTimer()
+
+		parentCancel()
+		for _, c := range children {
+			<-c.Done()
+		}
+
+		for _, cancel := range cancels {
+			cancel()
+		}
+	}
+}
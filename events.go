@@ -0,0 +1,193 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccx
+
+import (
+	"math/big"
+	"sync"
+	"time"
+)
+
+// EventKind identifies what happened to a node in an Event.
+type EventKind int
+
+const (
+	// Created fires when a node is first registered (Background, TODO, or
+	// WithIntent).
+	Created EventKind = iota
+	// Adjusted fires after SendAdjust mutates a node's Intent.Params.
+	Adjusted
+	// Fulfilled fires after Fulfill.
+	Fulfilled
+	// Aborted fires after Abort/AbortCause.
+	Aborted
+)
+
+// Event reports a single lifecycle or adjustment change for one node,
+// delivered to subscribers via Subscribe and replayable via EventsSince.
+type Event struct {
+	NodeID, ParentID string
+	Kind             EventKind
+	Intent           Intent
+	Cause            error
+	Timestamp        time.Time
+	Revision         uint64
+	// MatchedPolicies is a snapshot of the node's PolicyManager bitmap at
+	// publish time, so audit consumers can see which policies fired on
+	// this node without re-evaluating it themselves.
+	MatchedPolicies *big.Int
+}
+
+// eventBus is the process-wide fan-out that Background/TODO/WithIntent (via
+// publishCreated), applyAdjust, Fulfill, and AbortCause publish to, and
+// Subscribe/EventsSince read from.
+var eventBus = newBus()
+
+// maxRetainedEvents bounds how many Events bus.log keeps for EventsSince
+// replay. Without a bound, a long-running process retains every Event for
+// its whole lifetime; once the log exceeds this many entries, the oldest
+// are dropped and floor advances past their revisions.
+const maxRetainedEvents = 4096
+
+type bus struct {
+	mu sync.Mutex
+	// rev is the last revision handed out.
+	rev uint64
+	// floor is the revision below which log has been trimmed: EventsSince
+	// cannot replay events at or below floor, only ev.Revision > floor.
+	floor uint64
+	log   []Event
+	subs  []*subscription
+}
+
+type subscription struct {
+	nodeID string
+	scope  Scope
+	ch     chan Event
+}
+
+func newBus() *bus { return &bus{} }
+
+func (b *bus) publish(ev Event) {
+	b.mu.Lock()
+	b.rev++
+	ev.Revision = b.rev
+	b.log = append(b.log, ev)
+	if dropped := len(b.log) - maxRetainedEvents; dropped > 0 {
+		b.floor = b.log[dropped-1].Revision
+		b.log = append([]Event(nil), b.log[dropped:]...)
+	}
+	subs := append([]*subscription(nil), b.subs...)
+	b.mu.Unlock()
+
+	for _, s := range subs {
+		if !s.matches(ev.NodeID) {
+			continue
+		}
+		select {
+		case s.ch <- ev:
+		default:
+			// Slow subscriber; drop rather than block publishers. Callers
+			// that need every event should drain via EventsSince using the
+			// revision of the last event they did receive.
+		}
+	}
+}
+
+func (s *subscription) matches(nodeID string) bool {
+	if nodeID == s.nodeID {
+		return true
+	}
+	if s.scope == ScopeNode {
+		return false
+	}
+	return isDescendant(nodeID, s.nodeID)
+}
+
+// isDescendant reports whether nodeID's ancestry chain passes through
+// ancestorID. It walks ParentID links via get, so it also sees nodes known
+// only through a remote Registry.
+func isDescendant(nodeID, ancestorID string) bool {
+	n, ok := get(nodeID)
+	if !ok {
+		return false
+	}
+	for id := n.ParentID(); id != ""; {
+		if id == ancestorID {
+			return true
+		}
+		p, ok := get(id)
+		if !ok {
+			return false
+		}
+		id = p.ParentID()
+	}
+	return false
+}
+
+// Subscribe opens a feed of Events for this node. ScopeNode delivers only
+// this node's own events; ScopeSubtree delivers this node's and all current
+// and future descendants' events; ScopeRoot delivers every event for the
+// whole tree rooted at this node's Root. The returned func unsubscribes;
+// callers must call it to avoid leaking the subscription once they stop
+// reading. The channel itself is never closed: publish and unsubscribe can
+// race, and closing here would let a publish send on an already-closed
+// channel and panic. The channel is simply abandoned for the garbage
+// collector once unsubscribe has removed it from eventBus.subs.
+func (c *Ctx) Subscribe(scope Scope) (<-chan Event, func()) {
+	nodeID := c.id
+	if scope == ScopeRoot {
+		nodeID = c.Root().id
+	}
+	sub := &subscription{nodeID: nodeID, scope: scope, ch: make(chan Event, 64)}
+
+	eventBus.mu.Lock()
+	eventBus.subs = append(eventBus.subs, sub)
+	eventBus.mu.Unlock()
+
+	unsubscribe := func() {
+		eventBus.mu.Lock()
+		for i, s := range eventBus.subs {
+			if s == sub {
+				eventBus.subs = append(eventBus.subs[:i], eventBus.subs[i+1:]...)
+				break
+			}
+		}
+		eventBus.mu.Unlock()
+	}
+	return sub.ch, unsubscribe
+}
+
+// EventsSince returns every retained Event published after revision, in
+// publish order, so a reconnecting observer (a policy sidecar, tracing
+// exporter, or dashboard) can replay without losing events. Pass 0 to
+// replay everything retained.
+//
+// The bus only retains the most recent maxRetainedEvents entries: if
+// revision is at or below the bus's current floor, the events between
+// revision and floor are no longer available and the returned slice starts
+// past the gap rather than erroring. Callers that cannot tolerate a gap
+// should poll often enough that they never fall maxRetainedEvents behind.
+func EventsSince(revision uint64) []Event {
+	eventBus.mu.Lock()
+	defer eventBus.mu.Unlock()
+	out := make([]Event, 0, len(eventBus.log))
+	for _, ev := range eventBus.log {
+		if ev.Revision > revision {
+			out = append(out, ev)
+		}
+	}
+	return out
+}
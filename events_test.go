@@ -0,0 +1,63 @@
+package ccx_test
+
+import (
+	"testing"
+	"time"
+
+	ccx "github.com/ArieDeha/ccx"
+)
+
+func TestSubscribeSubtreeSeesCurrentAndFutureDescendants(t *testing.T) {
+	root := ccx.Background()
+	a, _ := ccx.WithIntent(root, ccx.Intent{Name: "A"}, ccx.Constraints{})
+
+	events, unsubscribe := a.Subscribe(ccx.ScopeSubtree)
+	defer unsubscribe()
+
+	// Future descendant: created after Subscribe.
+	b, _ := ccx.WithIntent(a, ccx.Intent{Name: "B"}, ccx.Constraints{})
+	b.Fulfill()
+
+	var sawCreated, sawFulfilled bool
+	for i := 0; i < 2; i++ {
+		select {
+		case ev := <-events:
+			if ev.NodeID != b.ID() {
+				t.Fatalf("expected events for B, got node %s", ev.NodeID)
+			}
+			switch ev.Kind {
+			case ccx.Created:
+				sawCreated = true
+			case ccx.Fulfilled:
+				sawFulfilled = true
+			}
+		case <-time.After(100 * time.Millisecond):
+			t.Fatal("timed out waiting for subtree event")
+		}
+	}
+	if !sawCreated || !sawFulfilled {
+		t.Fatalf("expected both Created and Fulfilled events, got created=%v fulfilled=%v", sawCreated, sawFulfilled)
+	}
+}
+
+func TestEventsSinceReplaysFromRevision(t *testing.T) {
+	root := ccx.Background()
+	before := ccx.EventsSince(0)
+	baseline := uint64(0)
+	if n := len(before); n > 0 {
+		baseline = before[n-1].Revision
+	}
+
+	root.Fulfill()
+
+	replayed := ccx.EventsSince(baseline)
+	found := false
+	for _, ev := range replayed {
+		if ev.NodeID == root.ID() && ev.Kind == ccx.Fulfilled {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("expected EventsSince to replay the Fulfilled event for root")
+	}
+}
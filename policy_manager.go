@@ -0,0 +1,163 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccx
+
+import (
+	"math/big"
+	"sync"
+)
+
+// PolicyManager assigns each policy ID a stable bit index the first time
+// EvaluatePolicies sees a decision from it, and tracks, per node, which
+// policy bits have already matched. This lets EvaluatePolicies skip
+// re-applying a ScopeSubtree/ScopeRoot decision that an ancestor already
+// satisfied, and lets IsRequiredBySignature tell an upstream filter whether
+// any policy bit is still outstanding for a given Intent name.
+//
+// A PolicyManager lives on the root *Ctx; nodes derived via WithIntent
+// share their root's instance (see (*Ctx).PolicyManager).
+type PolicyManager struct {
+	mu    sync.Mutex
+	bitOf map[string]uint // policy ID -> bit index
+	next  uint
+
+	matched   map[string]*big.Int     // node ID -> bitmap of policy bits matched on it
+	seenNames map[IntentName]*big.Int // Intent name -> bitmap of policy bits that have matched a node with this name
+}
+
+func newPolicyManager() *PolicyManager {
+	return &PolicyManager{
+		bitOf:     map[string]uint{},
+		matched:   map[string]*big.Int{},
+		seenNames: map[IntentName]*big.Int{},
+	}
+}
+
+func (m *PolicyManager) bitFor(policyID string) uint {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if b, ok := m.bitOf[policyID]; ok {
+		return b
+	}
+	b := m.next
+	m.bitOf[policyID] = b
+	m.next++
+	return b
+}
+
+// mark records that policyID matched a node with the given ID and Intent
+// name, returning the node's updated bitmap.
+func (m *PolicyManager) mark(nodeID string, name IntentName, policyID string) *big.Int {
+	bit := m.bitFor(policyID)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	bm, ok := m.matched[nodeID]
+	if !ok {
+		bm = new(big.Int)
+		m.matched[nodeID] = bm
+	}
+	bm.SetBit(bm, int(bit), 1)
+
+	seen, ok := m.seenNames[name]
+	if !ok {
+		seen = new(big.Int)
+		m.seenNames[name] = seen
+	}
+	seen.SetBit(seen, int(bit), 1)
+
+	return new(big.Int).Set(bm)
+}
+
+func (m *PolicyManager) matchedOf(nodeID string) *big.Int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if bm, ok := m.matched[nodeID]; ok {
+		return new(big.Int).Set(bm)
+	}
+	return new(big.Int)
+}
+
+// isMatched reports whether policyID has already matched nodeID.
+func (m *PolicyManager) isMatched(nodeID, policyID string) bool {
+	m.mu.Lock()
+	bit, known := m.bitOf[policyID]
+	bm := m.matched[nodeID]
+	m.mu.Unlock()
+	if !known || bm == nil {
+		return false
+	}
+	return bm.Bit(int(bit)) == 1
+}
+
+// satisfiedByAncestor reports whether policyID has already matched some
+// node on n's ancestor chain, so EvaluatePolicies can skip re-applying it
+// on n. Only the match is recorded, not the scope of the decision that
+// produced it, so an ancestor match of any scope counts.
+func (m *PolicyManager) satisfiedByAncestor(n *Ctx, policyID string) bool {
+	for cur := n.Parent(); cur != nil; cur = cur.Parent() {
+		if m.isMatched(cur.ID(), policyID) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsRequiredBySignature reports whether some policy bit the manager has
+// observed has not yet matched any node carrying Intent name. Upstream
+// filters can use this to skip building out work for a signature once
+// every policy known to care about it has already fired, and must keep
+// doing the work while it returns true.
+//
+// Only policies that have matched at least one node (of any name) have a
+// bit; a policy that has never matched any node, including one registered
+// after this call, is not considered, since the manager has no way to know
+// in advance which Intent names a policy.Policy.Match will accept. When no
+// policy has matched anything yet, it returns true.
+func (m *PolicyManager) IsRequiredBySignature(name IntentName) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.next == 0 {
+		return true
+	}
+	seen := m.seenNames[name]
+	if seen == nil {
+		return true
+	}
+	for bit := uint(0); bit < m.next; bit++ {
+		if seen.Bit(int(bit)) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// PolicyManager returns the PolicyManager shared by this node's tree.
+func (c *Ctx) PolicyManager() *PolicyManager {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.policyMgr
+}
+
+// IsRequiredBySignature is a convenience for c.PolicyManager().IsRequiredBySignature(name).
+func (c *Ctx) IsRequiredBySignature(name IntentName) bool {
+	return c.PolicyManager().IsRequiredBySignature(name)
+}
+
+// MatchedPolicies returns a snapshot of the bitmap of policy bits that have
+// matched this node so far, as tracked by its tree's PolicyManager.
+func (c *Ctx) MatchedPolicies() *big.Int {
+	return c.PolicyManager().matchedOf(c.id)
+}
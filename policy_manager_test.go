@@ -0,0 +1,60 @@
+package ccx_test
+
+import (
+	"testing"
+
+	ccx "github.com/ArieDeha/ccx"
+	policy "github.com/ArieDeha/ccxpolicy"
+)
+
+// AlwaysSubtreeCap adjusts transcode.targetQuality at ScopeSubtree every
+// time it matches, so the ancestor short-circuit in EvaluatePolicies is
+// exercised: without it, the same adjustment would be re-applied at every
+// descendant.
+type AlwaysSubtreeCap struct{}
+
+func (AlwaysSubtreeCap) ID() string               { return "always_subtree_cap" }
+func (AlwaysSubtreeCap) Priority() int            { return 1 }
+func (AlwaysSubtreeCap) Match(n policy.Node) bool { return n.Name() == "ManagerTest" }
+func (AlwaysSubtreeCap) Check(n policy.Node) []policy.Decision {
+	return []policy.Decision{{
+		PolicyID: "always_subtree_cap",
+		Scope:    policy.ScopeSubtree,
+		Action:   policy.ActionAdjust,
+		Adjust:   func(m map[string]any) { m["capped"] = true },
+		Reason:   policy.Reason("cap"),
+	}}
+}
+
+func TestEvaluatePoliciesSkipsAncestorSatisfiedSubtreeDecision(t *testing.T) {
+	policy.RegisterPolicy(AlwaysSubtreeCap{})
+
+	root := ccx.Background()
+	parent, _ := ccx.WithIntent(root, ccx.Intent{Name: "ManagerTest"}, ccx.Constraints{})
+	child, _ := ccx.WithIntent(parent, ccx.Intent{Name: "ManagerTest"}, ccx.Constraints{})
+
+	parentDecisions := ccx.EvaluatePolicies(parent)
+	if len(parentDecisions) != 1 {
+		t.Fatalf("expected parent's first evaluation to include the decision, got %d", len(parentDecisions))
+	}
+
+	childDecisions := ccx.EvaluatePolicies(child)
+	for _, d := range childDecisions {
+		if d.PolicyID == "always_subtree_cap" {
+			t.Fatal("expected child to skip a subtree decision already satisfied by its parent")
+		}
+	}
+
+	if parent.MatchedPolicies().BitLen() == 0 {
+		t.Fatal("expected parent's MatchedPolicies bitmap to record the match")
+	}
+}
+
+func TestIsRequiredBySignature(t *testing.T) {
+	root := ccx.Background()
+	node, _ := ccx.WithIntent(root, ccx.Intent{Name: "NeverSeen"}, ccx.Constraints{})
+
+	if !node.IsRequiredBySignature("NeverSeen") {
+		t.Fatal("expected an unobserved Intent name to still be required")
+	}
+}
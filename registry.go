@@ -0,0 +1,210 @@
+// Copyright 2025 Arieditya Pramadyana Deha <arieditya.prdh@live.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccx
+
+import (
+	"context"
+	"sync"
+)
+
+// Snapshot is a read-only, serializable view of a node's lineage metadata,
+// suitable for transport across a Registry backend that may live outside
+// this process.
+type Snapshot struct {
+	ID, ParentID string
+	Intent       Intent
+	Cons         Constraints
+	State        string
+	Err          error
+}
+
+// RegistryEventKind identifies what a RegistryEvent reports.
+type RegistryEventKind int
+
+const (
+	// RegistryPut reports that a node was registered or its snapshot changed.
+	RegistryPut RegistryEventKind = iota
+	// RegistryCancel reports a SendCancel; other processes sharing this
+	// lineage should turn it into a local Abort(Reason) on NodeID.
+	RegistryCancel
+	// RegistryAdjust reports a SendAdjust; other processes should apply
+	// Adjust to NodeID's Intent.Params.
+	RegistryAdjust
+)
+
+// RegistryEvent is what Registry.Watch delivers and Registry.Publish
+// broadcasts. Remote backends translate SendCancel/SendAdjust calls into
+// these events so every process watching the affected node(s) can mirror
+// the effect locally.
+type RegistryEvent struct {
+	Kind   RegistryEventKind
+	NodeID string
+	Scope  Scope
+	Reason error
+	Adjust AdjustFn
+}
+
+// Registry is the lineage backend nodes are recorded in and discovered
+// from. The default, installed automatically, is an in-process
+// implementation equivalent to ccx's original hard-wired sync.Map. Call
+// SetRegistry with a remote-backed implementation (see the ccxetcd
+// subpackage for an etcd v3 adapter) to make Parent/Root/Children and
+// scoped SendCancel/SendAdjust work across a lineage that spans processes.
+//
+// Implementations must be safe for concurrent use. Put/Get/Children answer
+// the "who is related to whom, and what is their last known state"
+// question; Watch/Publish carry live lifecycle and adjustment events.
+// Remote SendAdjust implementations should merge namespaced keys with
+// last-writer-wins semantics keyed on a monotonic revision, per the
+// idempotent-update guidance on SendAdjust.
+type Registry interface {
+	// Put records or updates a node's snapshot.
+	Put(snap Snapshot)
+	// Get returns the last known snapshot for id.
+	Get(id string) (Snapshot, bool)
+	// Children returns the IDs of nodes whose ParentID is id.
+	Children(id string) []string
+	// Watch returns a channel of events published for id. There is no
+	// call to stop a Watch: the in-memory Watch channel is never closed,
+	// and a remote backend closes it only when its own watch stream ends.
+	Watch(id string) <-chan RegistryEvent
+	// Publish broadcasts ev to anything watching ev.NodeID.
+	Publish(ev RegistryEvent)
+}
+
+// defaultRegistry is the in-process Registry installed before any call to
+// SetRegistry. register compares against it to decide whether a node needs
+// to watch the Registry for remote SendCancel/SendAdjust events: with the
+// default backend there is only one process in the lineage, so there is
+// nothing a Watch could ever report that SendCancel/SendAdjust didn't
+// already apply locally.
+var defaultRegistry Registry = newMemRegistry()
+
+// registry is the process-wide Registry backing Parent/Root/Children and
+// SendCancel/SendAdjust propagation. SetRegistry replaces it.
+var registry Registry = defaultRegistry
+
+// SetRegistry installs r as the lineage backend for every node from this
+// point forward. It must be called before any lineage that needs to span
+// processes is created; swapping it mid-flight does not retroactively
+// migrate already-registered nodes.
+func SetRegistry(r Registry) { registry = r }
+
+// ActiveRegistry returns the Registry currently backing Parent/Root/
+// Children and SendCancel/SendAdjust propagation.
+func ActiveRegistry() Registry { return registry }
+
+// memRegistry is the default in-process Registry: a straightforward
+// sync.Map keyed by node ID, matching ccx's original hard-wired behavior.
+type memRegistry struct {
+	snaps    sync.Map // id -> Snapshot
+	children sync.Map // id -> map[string]struct{}
+	subs     sync.Map // id -> []chan RegistryEvent
+	mu       sync.Mutex
+}
+
+func newMemRegistry() *memRegistry { return &memRegistry{} }
+
+func (r *memRegistry) Put(snap Snapshot) {
+	r.snaps.Store(snap.ID, snap)
+	if snap.ParentID == "" {
+		return
+	}
+	r.mu.Lock()
+	set, _ := r.children.LoadOrStore(snap.ParentID, map[string]struct{}{})
+	set.(map[string]struct{})[snap.ID] = struct{}{}
+	r.mu.Unlock()
+}
+
+func (r *memRegistry) Get(id string) (Snapshot, bool) {
+	v, ok := r.snaps.Load(id)
+	if !ok {
+		return Snapshot{}, false
+	}
+	return v.(Snapshot), true
+}
+
+func (r *memRegistry) Children(id string) []string {
+	v, ok := r.children.Load(id)
+	if !ok {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	set := v.(map[string]struct{})
+	out := make([]string, 0, len(set))
+	for childID := range set {
+		out = append(out, childID)
+	}
+	return out
+}
+
+func (r *memRegistry) Watch(id string) <-chan RegistryEvent {
+	ch := make(chan RegistryEvent, 16)
+	r.mu.Lock()
+	v, _ := r.subs.LoadOrStore(id, []chan RegistryEvent{})
+	r.subs.Store(id, append(v.([]chan RegistryEvent), ch))
+	r.mu.Unlock()
+	return ch
+}
+
+func (r *memRegistry) Publish(ev RegistryEvent) {
+	v, ok := r.subs.Load(ev.NodeID)
+	if !ok {
+		return
+	}
+	for _, ch := range v.([]chan RegistryEvent) {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
+
+func snapshotOf(c *Ctx) Snapshot {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return Snapshot{
+		ID:       c.id,
+		ParentID: c.parentID,
+		Intent:   c.intent,
+		Cons:     c.cons,
+		State:    c.state,
+		Err:      c.err,
+	}
+}
+
+// ctxFromSnapshot builds a detached, read-only *Ctx standing in for a node
+// known only through the Registry (e.g. one that lives in another
+// process). It reflects snap's state at the time it was fetched and is not
+// wired to the originating node's lifecycle, channels, or local children.
+func ctxFromSnapshot(snap Snapshot) *Ctx {
+	c := &Ctx{
+		Context:   context.Background(),
+		id:        snap.ID,
+		parentID:  snap.ParentID,
+		intent:    snap.Intent,
+		cons:      snap.Cons,
+		state:     snap.State,
+		err:       snap.Err,
+		doneCh:    make(chan struct{}),
+		clock:     realClock{},
+		policyMgr: newPolicyManager(),
+	}
+	if snap.State != "active" {
+		close(c.doneCh)
+	}
+	return c
+}
@@ -0,0 +1,138 @@
+package ccx_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	ccx "github.com/ArieDeha/ccx"
+)
+
+// doubleRegistry is a minimal Registry double standing in for a remote
+// backend: register only starts watchRegistry when a Registry other than
+// the default is installed, so installing this round-tripping Watch/Publish
+// is what gives watchRegistry's consume side something to exercise.
+type doubleRegistry struct {
+	mu       sync.Mutex
+	snaps    map[string]ccx.Snapshot
+	children map[string][]string
+	subs     map[string][]chan ccx.RegistryEvent
+}
+
+func newDoubleRegistry() *doubleRegistry {
+	return &doubleRegistry{
+		snaps:    map[string]ccx.Snapshot{},
+		children: map[string][]string{},
+		subs:     map[string][]chan ccx.RegistryEvent{},
+	}
+}
+
+func (d *doubleRegistry) Put(snap ccx.Snapshot) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.snaps[snap.ID] = snap
+	if snap.ParentID != "" {
+		d.children[snap.ParentID] = append(d.children[snap.ParentID], snap.ID)
+	}
+}
+
+func (d *doubleRegistry) Get(id string) (ccx.Snapshot, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	snap, ok := d.snaps[id]
+	return snap, ok
+}
+
+func (d *doubleRegistry) Children(id string) []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string(nil), d.children[id]...)
+}
+
+func (d *doubleRegistry) Watch(id string) <-chan ccx.RegistryEvent {
+	ch := make(chan ccx.RegistryEvent, 16)
+	d.mu.Lock()
+	d.subs[id] = append(d.subs[id], ch)
+	d.mu.Unlock()
+	return ch
+}
+
+func (d *doubleRegistry) Publish(ev ccx.RegistryEvent) {
+	d.mu.Lock()
+	subs := append([]chan ccx.RegistryEvent(nil), d.subs[ev.NodeID]...)
+	d.mu.Unlock()
+	for _, ch := range subs {
+		ch <- ev
+	}
+}
+
+func TestDefaultRegistryRecordsSnapshotAndChildren(t *testing.T) {
+	root := ccx.Background()
+	child, cancel := ccx.WithIntent(root, ccx.Intent{Name: "Child"}, ccx.Constraints{})
+	defer cancel()
+
+	snap, ok := ccx.ActiveRegistry().Get(child.ID())
+	if !ok {
+		t.Fatal("expected child to be registered in the default Registry")
+	}
+	if snap.ID != child.ID() || snap.ParentID != root.ID() {
+		t.Fatalf("unexpected snapshot %+v", snap)
+	}
+
+	kids := ccx.ActiveRegistry().Children(root.ID())
+	if len(kids) != 1 || kids[0] != child.ID() {
+		t.Fatalf("expected registry to know root's child, got %v", kids)
+	}
+}
+
+func TestRemoteRegistryCancelAppliedLocally(t *testing.T) {
+	prev := ccx.ActiveRegistry()
+	ccx.SetRegistry(newDoubleRegistry())
+	defer ccx.SetRegistry(prev)
+
+	root := ccx.Background()
+	node, cancel := ccx.WithIntent(root, ccx.Intent{Name: "Remote"}, ccx.Constraints{})
+	defer cancel()
+
+	reason := errors.New("remote cancel")
+	ccx.ActiveRegistry().Publish(ccx.RegistryEvent{Kind: ccx.RegistryCancel, NodeID: node.ID(), Scope: ccx.ScopeNode, Reason: reason})
+
+	select {
+	case <-node.Done():
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the remote cancel event to abort the node")
+	}
+	if node.State() != "aborted" {
+		t.Fatalf("expected aborted state, got %s", node.State())
+	}
+	if ccx.Cause(node) != reason {
+		t.Fatalf("expected Cause to be the remote reason, got %v", ccx.Cause(node))
+	}
+}
+
+func TestRemoteRegistryAdjustAppliedLocally(t *testing.T) {
+	prev := ccx.ActiveRegistry()
+	ccx.SetRegistry(newDoubleRegistry())
+	defer ccx.SetRegistry(prev)
+
+	root := ccx.Background()
+	node, cancel := ccx.WithIntent(root, ccx.Intent{Name: "Remote"}, ccx.Constraints{})
+	defer cancel()
+
+	ccx.ActiveRegistry().Publish(ccx.RegistryEvent{
+		Kind:   ccx.RegistryAdjust,
+		NodeID: node.ID(),
+		Scope:  ccx.ScopeNode,
+		Adjust: func(m map[string]any) { m["fromRemote"] = true },
+	})
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if v, _ := node.Intent().Params["fromRemote"].(bool); v {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("timed out waiting for the remote adjust event to apply locally")
+}